@@ -0,0 +1,189 @@
+package main
+
+/*
+	Config loading, validation and introspection.
+
+	loadConfig used to just decode the JSON file straight into cfg, so a
+	config missing a key silently rendered as a blank glyph and a
+	nonsensical pill_duration_secs was accepted without complaint. This file
+	adds three things on top of that: built-in defaults (via a `default`
+	struct tag), environment variable overrides (via an `env` tag, so
+	PACGO_PLAYER et al. override the file), and validation (via a
+	`validate` tag) that turns a bad config into a descriptive error instead
+	of a blank screen.
+
+	Precedence, low to high: default tag < config file < environment
+	variable.
+*/
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+/*
+	applyConfigDefaults fills every field of cfg that still has its zero
+	value from that field's `default` struct tag.
+*/
+func applyConfigDefaults(cfg *Config) error {
+	return eachConfigField(cfg, func(field reflect.Value, tag reflect.StructTag) error {
+		def, ok := tag.Lookup("default")
+		if !ok || !field.IsZero() {
+			return nil
+		}
+		return setFieldFromString(field, def)
+	})
+}
+
+/*
+	applyConfigEnv overrides any field whose `env` tag names a variable that
+	is actually set in the environment.
+*/
+func applyConfigEnv(cfg *Config) error {
+	return eachConfigField(cfg, func(field reflect.Value, tag reflect.StructTag) error {
+		envVar, ok := tag.Lookup("env")
+		if !ok {
+			return nil
+		}
+		raw, set := os.LookupEnv(envVar)
+		if !set {
+			return nil
+		}
+		return setFieldFromString(field, raw)
+	})
+}
+
+/*
+	validateConfig checks every field against its `validate` struct tag,
+	which may contain "required" (non-empty string) and/or "min=N" (numeric
+	fields must be >= N). Errors are returned in the style
+	"<json key>: <problem>", e.g. "pill_duration_secs: must be >= 1".
+*/
+func validateConfig(cfg *Config) error {
+	return eachConfigField(cfg, func(field reflect.Value, tag reflect.StructTag) error {
+		rule, ok := tag.Lookup("validate")
+		if !ok {
+			return nil
+		}
+		jsonKey := jsonKeyOf(tag)
+
+		for _, clause := range strings.Split(rule, ",") {
+			switch {
+			case clause == "required":
+				if field.Kind() == reflect.String && field.String() == "" {
+					return fmt.Errorf("%s: required", jsonKey)
+				}
+			case strings.HasPrefix(clause, "min="):
+				min, err := strconv.ParseInt(strings.TrimPrefix(clause, "min="), 10, 64)
+				if err != nil {
+					return fmt.Errorf("%s: invalid validate tag %q", jsonKey, clause)
+				}
+				if field.Int() < min {
+					return fmt.Errorf("%s: must be >= %d", jsonKey, min)
+				}
+			}
+		}
+		return nil
+	})
+}
+
+/*
+	eachConfigField walks the exported fields of *cfg via reflection,
+	calling fn with each field's addressable reflect.Value and struct tag.
+*/
+func eachConfigField(cfg *Config, fn func(field reflect.Value, tag reflect.StructTag) error) error {
+	v := reflect.ValueOf(cfg).Elem()
+	t := v.Type()
+
+	for i := 0; i < t.NumField(); i++ {
+		if err := fn(v.Field(i), t.Field(i).Tag); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func jsonKeyOf(tag reflect.StructTag) string {
+	return strings.Split(tag.Get("json"), ",")[0]
+}
+
+/*
+	setFieldFromString assigns raw to field, converting it according to the
+	field's kind (string, bool, or an integer/time.Duration field, since
+	PillDurationSecs et al. are declared as time.Duration but always treated
+	as a plain count of seconds elsewhere in this codebase).
+*/
+func setFieldFromString(field reflect.Value, raw string) error {
+	switch field.Kind() {
+	case reflect.String:
+		field.SetString(raw)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return fmt.Errorf("invalid bool %q: %w", raw, err)
+		}
+		field.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid integer %q: %w", raw, err)
+		}
+		field.SetInt(n)
+	default:
+		return fmt.Errorf("unsupported config field kind %s", field.Kind())
+	}
+	return nil
+}
+
+/*
+	runConfigCommand handles the `pacgo config ...` subcommand family. It's
+	dispatched directly from main before the regular game flags are parsed,
+	since "config" isn't a flag.
+*/
+func runConfigCommand(args []string) {
+	if len(args) == 0 || args[0] != "info" {
+		fmt.Fprintln(os.Stderr, "usage: pacgo config info [-config-file path]")
+		os.Exit(2)
+	}
+
+	fs := flag.NewFlagSet("config info", flag.ExitOnError)
+	configFile := fs.String("config-file", "source/config.json", "path to custom configuration file")
+	fs.Parse(args[1:])
+
+	printConfigInfo(*configFile)
+}
+
+/*
+	printConfigInfo prints every Config field: its JSON key, current value
+	(after loading *configFile the same way the game does), built-in
+	default, and the environment variable that can override it.
+*/
+func printConfigInfo(configFile string) {
+	if err := loadConfig(configFile); err != nil {
+		fmt.Fprintf(os.Stderr, "warning: failed to fully load %s: %v (showing defaults/env only)\n\n", configFile, err)
+		// loadConfig returns before its env step when the file can't be
+		// opened or decoded, so apply env overrides here as well.
+		if err := applyConfigEnv(&cfg); err != nil {
+			fmt.Fprintf(os.Stderr, "warning: %v\n\n", err)
+		}
+	}
+
+	v := reflect.ValueOf(cfg)
+	t := v.Type()
+
+	fmt.Printf("%-20s %-22s %-20s %-16s %s\n", "FIELD", "JSON KEY", "ENV VAR", "DEFAULT", "CURRENT")
+	for i := 0; i < t.NumField(); i++ {
+		tag := t.Field(i).Tag
+		fmt.Printf("%-20s %-22s %-20s %-16s %v\n",
+			t.Field(i).Name,
+			jsonKeyOf(tag),
+			tag.Get("env"),
+			tag.Get("default"),
+			v.Field(i).Interface(),
+		)
+	}
+}
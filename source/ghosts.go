@@ -0,0 +1,334 @@
+package main
+
+/*
+	Ghost AI
+
+	Before this file existed, moveGhosts() just called drawDirection() and picked
+	a random direction every tick (see the old Todo list in main.go). This file
+	replaces that with pluggable brains so different ghosts can behave
+	differently, and so frightened ghosts flee instead of wandering.
+*/
+
+import (
+	"sync"
+	"time"
+)
+
+// GhostBrain decides the next move for a single ghost, given the player's
+// position and the current maze layout. Implementations must return one of
+// "UP", "DOWN", "LEFT", "RIGHT" (matching the strings makeMove understands).
+type GhostBrain interface {
+	NextMove(g *ghost, player sprite, maze []string) string
+}
+
+// direction order mirrors drawDirection's map so random fallbacks stay consistent
+var directions = []string{"UP", "DOWN", "RIGHT", "LEFT"}
+
+// RandomBrain reproduces the original behaviour: pick a random direction.
+type RandomBrain struct{}
+
+func (RandomBrain) NextMove(g *ghost, player sprite, maze []string) string {
+	return drawDirection()
+}
+
+// BFSChaseBrain chases the player by breadth-first search, which is optimal
+// on an unweighted grid and cheap enough to rerun every tick since we only
+// ever need the first step of the path.
+type BFSChaseBrain struct{}
+
+func (BFSChaseBrain) NextMove(g *ghost, player sprite, maze []string) string {
+	return firstStepBFS(g.position.row, g.position.col, player.row, player.col, maze)
+}
+
+// AStarBrain chases the player using A* with a Manhattan distance heuristic.
+type AStarBrain struct{}
+
+func (AStarBrain) NextMove(g *ghost, player sprite, maze []string) string {
+	return firstStepAStar(g.position.row, g.position.col, player.row, player.col, maze)
+}
+
+// FleeBrain is used while a ghost is frightened (blue): it reverses the A*
+// heuristic, scoring neighbours by distance *from* the player rather than
+// distance to a target, so the ghost runs away instead of hunting.
+type FleeBrain struct{}
+
+func (FleeBrain) NextMove(g *ghost, player sprite, maze []string) string {
+	best := ""
+	bestDist := -1
+	for _, dir := range directions {
+		row, col := makeMove(g.position.row, g.position.col, dir)
+		if row == g.position.row && col == g.position.col {
+			continue // blocked by a wall
+		}
+		if dist := manhattan(row, col, player.row, player.col); dist > bestDist {
+			bestDist = dist
+			best = dir
+		}
+	}
+	if best == "" {
+		return drawDirection()
+	}
+	return best
+}
+
+type gridPos struct{ row, col int }
+
+func manhattan(row, col, targetRow, targetCol int) int {
+	return abs(row-targetRow) + abs(col-targetCol)
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+// neighbours returns the 4-connected neighbours of (row, col) that are not
+// walls, following the same tunnel-wrap rules as makeMove, paired with the
+// direction that reaches them.
+func neighbours(row, col int, maze []string) []struct {
+	pos gridPos
+	dir string
+} {
+	var result []struct {
+		pos gridPos
+		dir string
+	}
+	for _, dir := range directions {
+		newRow, newCol := makeMove(row, col, dir)
+		if newRow == row && newCol == col {
+			continue // wall, makeMove left us in place
+		}
+		result = append(result, struct {
+			pos gridPos
+			dir string
+		}{gridPos{newRow, newCol}, dir})
+	}
+	return result
+}
+
+// firstStepBFS runs a breadth-first search from (startRow, startCol) to
+// (targetRow, targetCol) and returns the direction of the first step on the
+// shortest path, or a random direction if no path exists.
+func firstStepBFS(startRow, startCol, targetRow, targetCol int, maze []string) string {
+	start := gridPos{startRow, startCol}
+	target := gridPos{targetRow, targetCol}
+	if start == target {
+		return drawDirection()
+	}
+
+	cameFrom := map[gridPos]gridPos{}
+	firstMove := map[gridPos]string{}
+	visited := map[gridPos]bool{start: true}
+	queue := []gridPos{start}
+
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+
+		if current == target {
+			return reconstructFirstStep(start, target, cameFrom, firstMove)
+		}
+
+		for _, n := range neighbours(current.row, current.col, maze) {
+			if visited[n.pos] {
+				continue
+			}
+			visited[n.pos] = true
+			cameFrom[n.pos] = current
+			if current == start {
+				firstMove[n.pos] = n.dir
+			} else {
+				firstMove[n.pos] = firstMove[current]
+			}
+			queue = append(queue, n.pos)
+		}
+	}
+
+	return drawDirection() // target unreachable
+}
+
+// firstStepAStar runs A* with a Manhattan distance heuristic and returns the
+// direction of the first step on the shortest path.
+func firstStepAStar(startRow, startCol, targetRow, targetCol int, maze []string) string {
+	start := gridPos{startRow, startCol}
+	target := gridPos{targetRow, targetCol}
+	if start == target {
+		return drawDirection()
+	}
+
+	cameFrom := map[gridPos]gridPos{}
+	firstMove := map[gridPos]string{}
+	gScore := map[gridPos]int{start: 0}
+	open := []gridPos{start}
+
+	for len(open) > 0 {
+		// Pick the open node with the lowest f = g + h. The open set stays
+		// small for a pacman maze, so a linear scan is fine here.
+		bestIdx := 0
+		bestF := gScore[open[0]] + manhattan(open[0].row, open[0].col, target.row, target.col)
+		for i, pos := range open {
+			f := gScore[pos] + manhattan(pos.row, pos.col, target.row, target.col)
+			if f < bestF {
+				bestF = f
+				bestIdx = i
+			}
+		}
+		current := open[bestIdx]
+		open = append(open[:bestIdx], open[bestIdx+1:]...)
+
+		if current == target {
+			return reconstructFirstStep(start, target, cameFrom, firstMove)
+		}
+
+		for _, n := range neighbours(current.row, current.col, maze) {
+			tentativeG := gScore[current] + 1
+			if existing, ok := gScore[n.pos]; ok && existing <= tentativeG {
+				continue
+			}
+			cameFrom[n.pos] = current
+			gScore[n.pos] = tentativeG
+			if current == start {
+				firstMove[n.pos] = n.dir
+			} else {
+				firstMove[n.pos] = firstMove[current]
+			}
+			open = append(open, n.pos)
+		}
+	}
+
+	return drawDirection() // target unreachable
+}
+
+// reconstructFirstStep walks the came-from chain back from target to start
+// and returns the direction taken on the very first hop, which is all a
+// ghost needs before the next recomputation.
+func reconstructFirstStep(start, target gridPos, cameFrom map[gridPos]gridPos, firstMove map[gridPos]string) string {
+	step := target
+	for {
+		prev, ok := cameFrom[step]
+		if !ok {
+			return drawDirection()
+		}
+		if prev == start {
+			break
+		}
+		step = prev
+	}
+	if dir, ok := firstMove[step]; ok {
+		return dir
+	}
+	return drawDirection()
+}
+
+// Scatter/chase alternation: ghosts periodically abandon the chase and head
+// to their assigned corner, classic arcade-style, so the player gets a
+// breather instead of being hunted constantly.
+type ghostMode string
+
+const (
+	modeScatter ghostMode = "Scatter"
+	modeChase   ghostMode = "Chase"
+)
+
+var (
+	currentGhostMode = modeScatter
+	modeTimer        *time.Timer
+	ghostModeMx      sync.RWMutex // guards currentGhostMode, written from the AfterFunc timer goroutine
+)
+
+// getGhostMode returns the current scatter/chase mode, safe to call from
+// any goroutine.
+func getGhostMode() ghostMode {
+	ghostModeMx.RLock()
+	defer ghostModeMx.RUnlock()
+	return currentGhostMode
+}
+
+// startGhostModeCycle kicks off the scatter/chase alternation using the
+// durations configured in cfg.ScatterSecs and cfg.ChaseSecs.
+func startGhostModeCycle() {
+	ghostModeMx.Lock()
+	currentGhostMode = modeScatter
+	ghostModeMx.Unlock()
+	scheduleNextGhostMode()
+}
+
+func scheduleNextGhostMode() {
+	ghostModeMx.RLock()
+	mode := currentGhostMode
+	ghostModeMx.RUnlock()
+
+	var next time.Duration
+	if mode == modeScatter {
+		next = time.Second * cfg.ScatterSecs
+	} else {
+		next = time.Second * cfg.ChaseSecs
+	}
+	modeTimer = time.AfterFunc(next, func() {
+		ghostModeMx.Lock()
+		if currentGhostMode == modeScatter {
+			currentGhostMode = modeChase
+		} else {
+			currentGhostMode = modeScatter
+		}
+		ghostModeMx.Unlock()
+		scheduleNextGhostMode()
+	})
+}
+
+// cornerFor returns the maze corner assigned to a ghost letter, used as its
+// scatter target. Ghosts fan out to the four corners of the maze.
+func cornerFor(letter byte, maze []string) sprite {
+	lastRow := len(maze) - 1
+	lastCol := len(maze[0]) - 1
+	var row, col int
+	switch letter {
+	case 'G':
+		row, col = 0, 0
+	case 'H':
+		row, col = 0, lastCol
+	case 'I':
+		row, col = lastRow, 0
+	default: // 'J' and anything else
+		row, col = lastRow, lastCol
+	}
+	return sprite{row, col, row, col}
+}
+
+// brainFor returns the chase-mode brain assigned to a ghost letter at load
+// time. Unrecognised letters fall back to RandomBrain.
+func brainFor(letter byte) GhostBrain {
+	switch letter {
+	case 'G':
+		return AStarBrain{}
+	case 'H':
+		return BFSChaseBrain{}
+	case 'I':
+		return AStarBrain{}
+	case 'J':
+		return BFSChaseBrain{}
+	default:
+		return RandomBrain{}
+	}
+}
+
+// brainByName looks up a GhostBrain by the name used in a level manifest's
+// ghost_brain_overrides (see LevelConfig in levels.go). ok is false for an
+// unrecognised name, so the caller can fall back to the load-time default
+// from brainFor instead of silently leaving a ghost brainless.
+func brainByName(name string) (brain GhostBrain, ok bool) {
+	switch name {
+	case "bfs":
+		return BFSChaseBrain{}, true
+	case "astar":
+		return AStarBrain{}, true
+	case "flee":
+		return FleeBrain{}, true
+	case "random":
+		return RandomBrain{}, true
+	default:
+		return nil, false
+	}
+}
@@ -0,0 +1,197 @@
+package main
+
+/*
+	Read-only HTTP spectator mode
+
+	When -http is set, a small http.Server runs alongside the game loop so a
+	browser (or a recording tool) can watch a game in progress without
+	touching the terminal renderer: GET /state is a single JSON snapshot,
+	GET /events streams the same snapshot over Server-Sent Events at the
+	game's own tick rate, and GET / serves a tiny embedded page that draws
+	the maze with the same glyphs as the terminal.
+*/
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+)
+
+//go:embed index.html
+var spectatorFS embed.FS
+
+// gameStateMx guards maze, player and ghost positions against concurrent
+// reads from the HTTP handlers while playLevel mutates them each tick.
+// ghostStatusMx (declared in main.go) continues to guard ghost.status on
+// its own, as it already did before this mode existed.
+var gameStateMx sync.RWMutex
+
+type playerState struct {
+	Row int `json:"row"`
+	Col int `json:"col"`
+}
+
+type ghostState struct {
+	Row    int    `json:"row"`
+	Col    int    `json:"col"`
+	Status string `json:"status"`
+}
+
+// glyphState mirrors the subset of Config used to render the maze, so the
+// spectator page stays in sync with whatever theme is loaded.
+type glyphState struct {
+	Wall      string `json:"wall"`
+	Dot       string `json:"dot"`
+	Pill      string `json:"pill"`
+	Fruit     string `json:"fruit"`
+	Player    string `json:"player"`
+	Ghost     string `json:"ghost"`
+	GhostBlue string `json:"ghost_blue"`
+	Space     string `json:"space"`
+}
+
+type stateSnapshot struct {
+	Maze   []string     `json:"maze"`
+	Player playerState  `json:"player"`
+	Ghosts []ghostState `json:"ghosts"`
+	Score  int          `json:"score"`
+	Lives  int          `json:"lives"`
+	Level  int          `json:"level"`
+	Glyphs glyphState   `json:"glyphs"`
+}
+
+/*
+	snapshotState copies maze, player and ghosts under lock, so handlers can
+	serialise them without racing the game loop.
+*/
+func snapshotState() stateSnapshot {
+	// ghosts (the slice header and each ghost's position) is mutated under
+	// gameStateMx by moveGhosts/filterGhostsByLetters, not ghostStatusMx -
+	// only g.status is guarded by that one. playLevel always takes
+	// gameStateMx before (and around) its nested ghostStatusMx use, so this
+	// matches that same acquisition order to avoid deadlocking against it.
+	gameStateMx.RLock()
+	mazeCopy := make([]string, len(maze))
+	copy(mazeCopy, maze)
+	playerCopy := playerState{Row: player.row, Col: player.col}
+	scoreCopy := score
+	livesCopy := lives
+	level := 0
+	if currentGame != nil {
+		level = currentGame.LevelIndex + 1
+	}
+
+	ghostStatusMx.RLock()
+	ghostsCopy := make([]ghostState, len(ghosts))
+	for i, g := range ghosts {
+		ghostsCopy[i] = ghostState{Row: g.position.row, Col: g.position.col, Status: string(g.status)}
+	}
+	ghostStatusMx.RUnlock()
+
+	gameStateMx.RUnlock()
+
+	return stateSnapshot{
+		Maze:   mazeCopy,
+		Player: playerCopy,
+		Ghosts: ghostsCopy,
+		Score:  scoreCopy,
+		Lives:  livesCopy,
+		Level:  level,
+		Glyphs: glyphState{
+			Wall:      cfg.Wall,
+			Dot:       cfg.Dot,
+			Pill:      cfg.Pill,
+			Fruit:     cfg.Fruit,
+			Player:    cfg.Player,
+			Ghost:     cfg.Ghost,
+			GhostBlue: cfg.GhostBlue,
+			Space:     cfg.Space,
+		},
+	}
+}
+
+// eventsInterval returns the active level's tick interval, so the SSE stream
+// in handleEvents tracks whatever PlayerSpeedRatio the level sets instead of
+// assuming the base rate. Falls back to baseTickInterval before a Game
+// exists (currentGame is nil briefly on startup, before main sets it).
+func eventsInterval() time.Duration {
+	gameStateMx.RLock()
+	defer gameStateMx.RUnlock()
+	if currentGame == nil || currentGame.tickInterval == 0 {
+		return baseTickInterval
+	}
+	return currentGame.tickInterval
+}
+
+func handleState(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(snapshotState()); err != nil {
+		log.Println("failed to encode state snapshot:", err)
+	}
+}
+
+/*
+	handleEvents streams a state snapshot over Server-Sent Events once per
+	game tick, until the client disconnects.
+*/
+func handleEvents(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+
+	// currentGame's tickInterval tracks whatever the active level's
+	// PlayerSpeedRatio works out to (see levels.go), so read it fresh each
+	// time rather than hardcoding baseTickInterval, which only holds at a
+	// ratio of 1. Sends the full snapshot each tick rather than an actual
+	// delta - simpler, and cheap enough at this size of state that it
+	// hasn't been worth the bookkeeping a real diff would need.
+	ticker := time.NewTicker(eventsInterval())
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-ticker.C:
+			data, err := json.Marshal(snapshotState())
+			if err != nil {
+				log.Println("failed to marshal state snapshot:", err)
+				return
+			}
+			if _, err := fmt.Fprintf(w, "data: %s\n\n", data); err != nil {
+				return
+			}
+			flusher.Flush()
+			ticker.Reset(eventsInterval()) // picks up a level's tick interval changing mid-stream
+		}
+	}
+}
+
+/*
+	startHTTPServer starts the spectator HTTP server on addr in the
+	background. It never blocks the game loop: a failure is logged, not
+	fatal, since spectating is optional.
+*/
+func startHTTPServer(addr string) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/state", handleState)
+	mux.HandleFunc("/events", handleEvents)
+	mux.Handle("/", http.FileServer(http.FS(spectatorFS)))
+
+	go func() {
+		log.Println("spectator mode listening on", addr)
+		if err := http.ListenAndServe(addr, mux); err != nil {
+			log.Println("spectator http server stopped:", err)
+		}
+	}()
+}
@@ -0,0 +1,359 @@
+package main
+
+/*
+	Level progression
+
+	Originally pacgo only ever loaded a single maze and a single config file
+	and ran until the player died or cleared it. This file adds a LevelPack:
+	an ordered set of mazes with per-level overrides, wired together by a
+	Game struct that owns the run loop (Game.Run, called from main).
+*/
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/danicat/simpleansi"
+)
+
+// LevelConfig describes one entry in a level pack's manifest. Any field left
+// at its zero value falls back to the pack/global default, except
+// PillDurationSecs: a level's override is written into cfg and stays in
+// effect for later levels that leave it at zero.
+type LevelConfig struct {
+	MazeFile         string  `json:"maze_file"`
+	PillDurationSecs int     `json:"pill_duration_secs,omitempty"`
+	GhostBrains      string  `json:"ghost_brains,omitempty"` // letters to enable, e.g. "GHIJ"
+	// GhostBrainOverrides reassigns the chase-mode brain of specific
+	// surviving ghosts for this level, keyed by spawn letter with values
+	// from brainByName (e.g. "bfs", "astar", "flee", "random"). Ghosts not
+	// named here keep the default brainFor assigns at maze load time.
+	GhostBrainOverrides map[string]string `json:"ghost_brain_overrides,omitempty"`
+	GhostSpeedRatio     float64           `json:"ghost_speed_ratio,omitempty"`
+	PlayerSpeedRatio    float64           `json:"player_speed_ratio,omitempty"`
+	ScoreTarget         int               `json:"score_target,omitempty"`
+	FruitAfterDots      int               `json:"fruit_after_dots,omitempty"`
+}
+
+// baseTickInterval is the game loop's tick rate at a PlayerSpeedRatio of 1.
+const baseTickInterval = 150 * time.Millisecond
+
+// LevelPack is the decoded form of a pack directory's levels.json.
+type LevelPack struct {
+	Name   string        `json:"name"`
+	Levels []LevelConfig `json:"levels"`
+}
+
+/*
+	Loads and decodes a level pack manifest (levels.json) from the given
+	directory. Maze file paths inside the manifest are resolved relative to
+	this directory.
+*/
+func loadLevelPack(dir string) (*LevelPack, error) {
+	f, err := os.Open(filepath.Join(dir, "levels.json"))
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var pack LevelPack
+	if err := json.NewDecoder(f).Decode(&pack); err != nil {
+		return nil, err
+	}
+	if len(pack.Levels) == 0 {
+		return nil, fmt.Errorf("level pack %q defines no levels", dir)
+	}
+
+	return &pack, nil
+}
+
+// Game owns a single playthrough: the current level's maze/player/ghosts,
+// running score and lives, and which level of the pack is active. The
+// movement and rendering helpers (movePlayer, moveGhosts, printScreen, ...)
+// still operate on the package-level globals; Game is responsible for
+// pointing those globals at the right level and keeping score/lives across
+// the transition.
+type Game struct {
+	pack       *LevelPack
+	packDir    string
+	LevelIndex int
+
+	fruitSpawned   bool
+	dotsEatenSince int
+
+	// Per-level pacing/goal state, set by loadLevel from that level's
+	// GhostSpeedRatio/PlayerSpeedRatio/ScoreTarget overrides.
+	tickInterval    time.Duration
+	ghostSpeedRatio float64
+	ghostMoveAcc    float64
+	scoreTarget     int
+}
+
+/*
+	Creates a Game for the given pack, starting at startLevel (0-indexed).
+	Score and lives (the package-level score/lives globals) are left at
+	their zero-value/default state and carry across levels as the pack
+	advances.
+*/
+func NewGame(pack *LevelPack, packDir string, startLevel int) (*Game, error) {
+	g := &Game{pack: pack, packDir: packDir}
+	if err := g.loadLevel(startLevel); err != nil {
+		return nil, err
+	}
+	return g, nil
+}
+
+/*
+	Loads the level at index into the package-level maze/player/ghosts
+	globals, applying that level's config overrides on top of cfg.
+*/
+func (g *Game) loadLevel(index int) error {
+	if index < 0 || index >= len(g.pack.Levels) {
+		return fmt.Errorf("level %d out of range for pack %q", index, g.pack.Name)
+	}
+	level := g.pack.Levels[index]
+
+	gameStateMx.Lock()
+	maze = nil
+	ghosts = nil
+	numDots = 0
+
+	mazePath := filepath.Join(g.packDir, level.MazeFile)
+	err := loadMaze(mazePath)
+	if err == nil {
+		if level.PillDurationSecs > 0 {
+			cfg.PillDurationSecs = time.Duration(level.PillDurationSecs)
+		}
+		if level.GhostBrains != "" {
+			filterGhostsByLetters(level.GhostBrains)
+		}
+		if len(level.GhostBrainOverrides) > 0 {
+			applyGhostBrainOverrides(level.GhostBrainOverrides)
+		}
+		g.LevelIndex = index
+
+		// tickInterval is read by the HTTP spectator mode's handleEvents
+		// from another goroutine (see eventsInterval), so it's set under
+		// gameStateMx alongside LevelIndex rather than after unlocking.
+		playerSpeedRatio := level.PlayerSpeedRatio
+		if playerSpeedRatio <= 0 {
+			playerSpeedRatio = 1
+		}
+		g.tickInterval = time.Duration(float64(baseTickInterval) / playerSpeedRatio)
+	}
+	gameStateMx.Unlock()
+	if err != nil {
+		return fmt.Errorf("loading maze for level %d: %w", index, err)
+	}
+
+	g.fruitSpawned = false
+	g.dotsEatenSince = 0
+
+	g.ghostSpeedRatio = level.GhostSpeedRatio
+	if g.ghostSpeedRatio <= 0 {
+		g.ghostSpeedRatio = 1
+	}
+	g.ghostMoveAcc = 0
+
+	g.scoreTarget = level.ScoreTarget
+
+	return nil
+}
+
+// filterGhostsByLetters keeps only the ghosts whose maze spawn letter
+// appears in letters, so a level can enable a specific subset (e.g. "HJ"
+// for just the two BFS-brained ghosts) without needing a different maze
+// file.
+func filterGhostsByLetters(letters string) {
+	kept := ghosts[:0]
+	for _, g := range ghosts {
+		if strings.IndexByte(letters, g.letter) >= 0 {
+			kept = append(kept, g)
+		}
+	}
+	ghosts = kept
+}
+
+// applyGhostBrainOverrides swaps in a level-specific GhostBrain for any
+// surviving ghost whose letter appears in overrides, so a level can e.g.
+// make ghost G use AStarBrain in one level and BFSChaseBrain in another
+// without needing a different maze file. Ghosts not named in overrides, and
+// unrecognised brain names, are left at whatever brainFor assigned at load.
+func applyGhostBrainOverrides(overrides map[string]string) {
+	for _, g := range ghosts {
+		name, ok := overrides[string(g.letter)]
+		if !ok {
+			continue
+		}
+		brain, ok := brainByName(name)
+		if !ok {
+			log.Printf("level pack: unrecognised ghost brain %q for letter %c, keeping default", name, g.letter)
+			continue
+		}
+		g.brain = brain
+	}
+}
+
+/*
+	fruitValue returns the score a fruit is worth on the current level: a
+	base value scaled up by level index, the classic pacman progression.
+*/
+func (g *Game) fruitValue() int {
+	return 100 * (g.LevelIndex + 1)
+}
+
+// fruitSpawnTile picks where the fruit appears: the empty (' ') tile nearest
+// the centre of the maze, so the fruit never covers a dot, pill or wall.
+func fruitSpawnTile() (row, col int, ok bool) {
+	centreRow, centreCol := len(maze)/2, len(maze[0])/2
+	for radius := 0; radius < len(maze)+len(maze[0]); radius++ {
+		for dr := -radius; dr <= radius; dr++ {
+			for dc := -radius; dc <= radius; dc++ {
+				r, c := centreRow+dr, centreCol+dc
+				if r < 0 || r >= len(maze) || c < 0 || c >= len(maze[r]) {
+					continue
+				}
+				if maze[r][c] == ' ' {
+					return r, c, true
+				}
+			}
+		}
+	}
+	return 0, 0, false
+}
+
+/*
+	onDotEaten is called by movePlayer each time a dot is eaten. Once
+	FruitAfterDots dots have been eaten on this level, it spawns a single
+	bonus fruit tile.
+*/
+func (g *Game) onDotEaten() {
+	level := g.pack.Levels[g.LevelIndex]
+	if level.FruitAfterDots <= 0 || g.fruitSpawned {
+		return
+	}
+
+	g.dotsEatenSince++
+	if g.dotsEatenSince < level.FruitAfterDots {
+		return
+	}
+
+	if row, col, ok := fruitSpawnTile(); ok {
+		maze[row] = maze[row][0:col] + "F" + maze[row][col+1:]
+		g.fruitSpawned = true
+	}
+}
+
+/*
+	Runs the game: plays the current level to completion (cleared or out of
+	lives), shows a between-level splash, and advances to the next level in
+	the pack. Returns once the player runs out of lives or the pack is
+	finished.
+*/
+func (g *Game) Run(input <-chan string) {
+	for {
+		playLevel(g, input)
+
+		if lives <= 0 {
+			return
+		}
+
+		if g.LevelIndex == len(g.pack.Levels)-1 {
+			printLevelSplash(fmt.Sprintf("Pack complete! Final score: %d", score))
+			return
+		}
+
+		printLevelSplash(fmt.Sprintf("Level %d clear! Score: %d", g.LevelIndex+1, score))
+		if err := g.loadLevel(g.LevelIndex + 1); err != nil {
+			log.Println("failed to load next level:", err)
+			return
+		}
+	}
+}
+
+/*
+	playLevel runs the per-tick game loop for the current level: read input,
+	move player and ghosts, resolve collisions, render, and return once the
+	level is cleared (numDots == 0, or the level's ScoreTarget is reached) or
+	the player is out of lives.
+
+	This is the loop that used to live inline in main(); it's now reusable
+	across levels via Game.Run.
+*/
+func playLevel(g *Game, input <-chan string) {
+	for {
+		gameStateMx.Lock()
+
+		select {
+		case inp := <-input:
+			if inp == "ESC" {
+				lives = 0
+			}
+			movePlayer(inp)
+		default:
+		}
+
+		// GhostSpeedRatio scales how often ghosts move relative to the base
+		// tick rate: accumulate fractional moves per tick and spend whole
+		// ones, so e.g. a ratio of 0.5 moves ghosts every other tick and 2
+		// moves them twice in one.
+		g.ghostMoveAcc += g.ghostSpeedRatio
+		for g.ghostMoveAcc >= 1 {
+			moveGhosts()
+			g.ghostMoveAcc--
+		}
+
+		for _, ghst := range ghosts {
+			if player.row == ghst.position.row && player.col == ghst.position.col {
+				ghostStatusMx.RLock()
+				if ghst.status == GhostStatusNormal {
+					lives--
+					if lives != 0 {
+						moveCursor(player.row, player.col)
+						fmt.Print(cfg.Death)
+						moveCursor(len(maze)+2, 0)
+						ghostStatusMx.RUnlock()
+						time.Sleep(1000 * time.Millisecond)
+						player.row, player.col = player.startRow, player.startRow
+					}
+				} else if ghst.status == GhostStatusBlue {
+					ghostStatusMx.RUnlock()
+					updateGhosts([]*ghost{ghst}, GhostStatusNormal)
+					ghst.position.row, ghst.position.col = ghst.position.startRow, ghst.position.startCol
+				}
+			}
+		}
+
+		gameStateMx.Unlock()
+
+		printScreen()
+
+		levelCleared := numDots == 0 || (g.scoreTarget > 0 && score >= g.scoreTarget)
+		if levelCleared || lives <= 0 {
+			if lives == 0 {
+				moveCursor(player.row, player.col)
+				fmt.Print(cfg.Death)
+				moveCursor(len(maze)+2, 0)
+			}
+			return
+		}
+
+		time.Sleep(g.tickInterval)
+	}
+}
+
+/*
+	printLevelSplash clears the screen and shows a message for a couple of
+	seconds between levels.
+*/
+func printLevelSplash(message string) {
+	simpleansi.ClearScreen()
+	moveCursor(len(maze)/2, 0)
+	fmt.Println(message)
+	time.Sleep(2 * time.Second)
+}
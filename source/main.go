@@ -66,7 +66,7 @@ package main
 	Todo:
 	[ ] Centre the screen
 	[ ] Add themes option - just change the files that are loaded
-	[ ] Add pathfinding to AI
+	[x] Add pathfinding to AI
 	[ ] Add new maps - could have a level select
 */
 
@@ -81,6 +81,7 @@ import (
 	"math/rand"
 	"os"
 	"os/exec"
+	"path/filepath"
 	"strconv"
 	"sync"
 	"time"
@@ -102,16 +103,19 @@ type sprite struct {
 // Config struct for holding Json data
 // Note that public members were used here - required for json decoder to work!
 type Config struct {
-	Player           string        `json:"player"`
-	Ghost            string        `json:"ghost"`
-	GhostBlue        string        `json:"ghost_blue"`
-	Wall             string        `json:"wall"`
-	Dot              string        `json:"dot"`
-	Pill             string        `json:"pill"`
-	Death            string        `json:"death"`
-	Space            string        `json:"space"`
-	UseEmoji         bool          `json:"use_emoji"`
-	PillDurationSecs time.Duration `json:"pill_duration_secs"`
+	Player           string        `json:"player" env:"PACGO_PLAYER" default:"😃" validate:"required"`
+	Ghost            string        `json:"ghost" env:"PACGO_GHOST" default:"👻" validate:"required"`
+	GhostBlue        string        `json:"ghost_blue" env:"PACGO_GHOST_BLUE" default:"🤖"`
+	Wall             string        `json:"wall" env:"PACGO_WALL" default:"🌵" validate:"required"`
+	Dot              string        `json:"dot" env:"PACGO_DOT" default:"🍕" validate:"required"`
+	Pill             string        `json:"pill" env:"PACGO_PILL" default:"💊"`
+	Death            string        `json:"death" env:"PACGO_DEATH" default:"💀"`
+	Space            string        `json:"space" env:"PACGO_SPACE" default:"  "`
+	UseEmoji         bool          `json:"use_emoji" env:"PACGO_USE_EMOJI" default:"true"`
+	PillDurationSecs time.Duration `json:"pill_duration_secs" env:"PACGO_PILL_DURATION_SECS" default:"10" validate:"min=1"`
+	ScatterSecs      time.Duration `json:"scatter_secs" env:"PACGO_SCATTER_SECS" default:"7" validate:"min=1"`
+	ChaseSecs        time.Duration `json:"chase_secs" env:"PACGO_CHASE_SECS" default:"20" validate:"min=1"`
+	Fruit            string        `json:"fruit" env:"PACGO_FRUIT" default:"🍒"`
 }
 
 type GhostStatus string // Define the Ghost status as a string
@@ -125,6 +129,9 @@ const (
 type ghost struct {
 	position sprite
 	status   GhostStatus
+	brain    GhostBrain // chase-mode brain, assigned per ghost letter at load time
+	corner   sprite     // scatter-mode target tile
+	letter   byte       // maze spawn letter ('G', 'H', 'I' or 'J') this ghost was loaded from
 }
 
 // Global variables
@@ -137,6 +144,11 @@ var score int
 var numDots int
 var lives = 3
 
+// currentGame is set by main once the active Game is constructed, so that
+// package-level helpers like movePlayer can report dot/fruit events to it
+// without needing the whole game loop threaded through as a parameter.
+var currentGame *Game
+
 var pillTimer *time.Timer
 var pillMx sync.Mutex          // Mutex lock
 var ghostStatusMx sync.RWMutex // Read/Write Mutex
@@ -149,6 +161,9 @@ var ghostStatusMx sync.RWMutex // Read/Write Mutex
 var (
 	configFile = flag.String("config-file", "source/config.json", "path to custom configuration file")
 	mazeFile   = flag.String("maze-file", "source/maze01.txt", "path to a custom maze file")
+	packDir    = flag.String("pack", "", "path to a directory containing a levels.json level pack; overrides -maze-file")
+	startLevel = flag.Int("level", 1, "1-indexed level to start at within the pack (requires -pack)")
+	httpAddr   = flag.String("http", "", "address (e.g. :8080) to serve a read-only HTTP spectator mode on; disabled if empty")
 )
 
 // ---------------- Initialisation functions ----------------
@@ -193,8 +208,15 @@ func loadMaze(file string) error {
 			switch char {
 			case 'P':
 				player = sprite{row, col, row, col}
-			case 'G':
-				ghosts = append(ghosts, &ghost{sprite{row, col, row, col}, GhostStatusNormal}) // & here means we are adding a pointer to an object
+			case 'G', 'H', 'I', 'J':
+				pos := sprite{row, col, row, col}
+				ghosts = append(ghosts, &ghost{
+					position: pos,
+					status:   GhostStatusNormal,
+					brain:    brainFor(byte(char)),
+					corner:   cornerFor(byte(char), maze),
+					letter:   byte(char),
+				})
 			case '.':
 				numDots++
 			}
@@ -205,28 +227,37 @@ func loadMaze(file string) error {
 }
 
 /*
-	Loads the json config, decodes it, and stores it in the cfg variable
+	Loads the json config, decodes it, and stores it in the cfg variable.
+
+	Values are resolved in precedence order, lowest to highest: the
+	`default` struct tag on Config, then the decoded file, then any `env`
+	tag whose environment variable is set. The result is validated against
+	each field's `validate` struct tag before returning.
 
-	Returns an error if the file cannot be opened or there is an error when decoding the json file.
+	Returns an error if the file cannot be opened, the json cannot be
+	decoded, an env var holds a value of the wrong type, or validation fails.
 */
 func loadConfig(file string) error {
-	f, err := os.Open(file) // Open file
+	if err := applyConfigDefaults(&cfg); err != nil {
+		return err
+	}
 
-	// Error checking, returns error
+	f, err := os.Open(file) // Open file
 	if err != nil {
 		return err
 	}
 	defer f.Close() // Defer file closing to end of function
 
 	decoder := json.NewDecoder(f) // Create json decoder
-	err = decoder.Decode(&cfg)    // Decode the json into the config struct
+	if err := decoder.Decode(&cfg); err != nil {
+		return err
+	}
 
-	// Error checking
-	if err != nil {
+	if err := applyConfigEnv(&cfg); err != nil {
 		return err
 	}
 
-	return nil
+	return validateConfig(&cfg)
 }
 
 // ---------------- Game Logic functions ----------------
@@ -290,10 +321,18 @@ func movePlayer(dir string) {
 		numDots--
 		score++
 		removeDot(player.row, player.col)
+		if currentGame != nil {
+			currentGame.onDotEaten()
+		}
 	case 'X':
 		score += 10
 		removeDot(player.row, player.col)
 		go processPill() // Call goroutine
+	case 'F':
+		if currentGame != nil {
+			score += currentGame.fruitValue()
+		}
+		removeDot(player.row, player.col)
 	}
 }
 
@@ -314,11 +353,29 @@ func drawDirection() string {
 }
 
 /*
-	Moves the ghosts by drawing a random direction and moving them in that direction
+	Moves each ghost according to its brain.
+
+	A frightened (blue) ghost always flees the player. Otherwise the ghost
+	chases the player using its assigned brain during Chase mode, or heads to
+	its assigned corner during Scatter mode (see ghosts.go).
 */
 func moveGhosts() {
 	for _, g := range ghosts {
-		dir := drawDirection()
+		var dir string
+
+		ghostStatusMx.RLock()
+		frightened := g.status == GhostStatusBlue
+		ghostStatusMx.RUnlock()
+
+		switch {
+		case frightened:
+			dir = FleeBrain{}.NextMove(g, player, maze)
+		case getGhostMode() == modeScatter:
+			dir = g.brain.NextMove(g, g.corner, maze)
+		default:
+			dir = g.brain.NextMove(g, player, maze)
+		}
+
 		g.position.row, g.position.col = makeMove(g.position.row, g.position.col, dir)
 	}
 }
@@ -382,6 +439,8 @@ func printScreen() {
 				fmt.Print(cfg.Dot)
 			case 'X':
 				fmt.Print(cfg.Pill)
+			case 'F':
+				fmt.Print(cfg.Fruit)
 			default:
 				fmt.Print(cfg.Space)
 			}
@@ -429,38 +488,86 @@ func getLivesAsEmoji() string {
 	return buf.String() // Return the buffer
 }
 
+// rawRead is one Stdin.Read result: the bytes actually read (already sliced
+// to length) and any error that Read returned alongside them.
+type rawRead struct {
+	buf []byte
+	err error
+}
+
 /*
-	Reads input from Stdin (100 byte buffer)
-	Returns the command read in (ESC, Up, down, etc.) and an error code
+	runStdinReader is the ONLY goroutine in the program allowed to call
+	os.Stdin.Read. It runs for the lifetime of the process, forwarding each
+	read as a rawRead on ch. Every other piece of code that wants
+	keystrokes (the game loop's direction parser, the title screen's "press
+	any key", the initials prompt) consumes from ch or a channel derived
+	from it instead of reading Stdin itself - concurrent blocking reads on
+	the same fd are a race (two readers can each get some of the bytes
+	meant for the other, and any reader with nobody left to notify just
+	blocks forever), so there must only ever be one.
 */
-func readInput() (string, error) {
-	buffer := make([]byte, 100)
-	cnt, err := os.Stdin.Read(buffer)
-
-	// If error, return error and empty string
-	if err != nil {
-		return "", err
+func runStdinReader(ch chan<- rawRead) {
+	for {
+		buffer := make([]byte, 100)
+		cnt, err := os.Stdin.Read(buffer)
+		ch <- rawRead{buf: buffer[:cnt], err: err}
+		if err != nil {
+			log.Println("error reading input:", err)
+			return
+		}
 	}
+}
 
-	// If the key press is esc
-	if cnt == 1 && buffer[0] == 0x1b {
-		return "ESC", nil
-	} else if cnt >= 3 {
-		if buffer[0] == 0x1b && buffer[1] == '[' {
-			switch buffer[2] {
+/*
+	parseDirection interprets one rawRead's bytes as a direction command
+	(ESC, UP, DOWN, LEFT, RIGHT), or "" if the bytes don't form one.
+*/
+func parseDirection(buf []byte) string {
+	if len(buf) == 1 && buf[0] == 0x1b {
+		return "ESC"
+	} else if len(buf) >= 3 {
+		if buf[0] == 0x1b && buf[1] == '[' {
+			switch buf[2] {
 			case 'A':
-				return "UP", nil
+				return "UP"
 			case 'B':
-				return "DOWN", nil
+				return "DOWN"
 			case 'C':
-				return "RIGHT", nil
+				return "RIGHT"
 			case 'D':
-				return "LEFT", nil
+				return "LEFT"
 			}
 		}
 	}
 
-	return "", nil // Nothing read in
+	return "" // Nothing recognised
+}
+
+/*
+	translateInput reads rawReads from raw and forwards parsed direction
+	commands to out, until done is closed. It's the consumer that keeps the
+	game loop's input channel fed while runStdinReader keeps running
+	underneath it; closing done hands raw's next message to whoever reads
+	from raw next (e.g. promptInitials) instead.
+*/
+func translateInput(raw <-chan rawRead, out chan<- string, done <-chan struct{}, stopped chan<- struct{}) {
+	defer close(stopped)
+	for {
+		select {
+		case <-done:
+			return
+		case r := <-raw:
+			dir := "ESC"
+			if r.err == nil {
+				dir = parseDirection(r.buf)
+			}
+			select {
+			case out <- dir:
+			case <-done:
+				return
+			}
+		}
+	}
 }
 
 // ---------------- Close-down functions ----------------
@@ -477,7 +584,26 @@ func cleanup() {
 
 // Main function
 
+/*
+	singleMazePack wraps the legacy -maze-file flag in a one-level LevelPack
+	so Game.Run can drive it the same way it drives a real pack.
+*/
+func singleMazePack(mazeFile string) (*LevelPack, string) {
+	dir, file := filepath.Split(mazeFile)
+	return &LevelPack{
+		Name:   "default",
+		Levels: []LevelConfig{{MazeFile: file}},
+	}, dir
+}
+
 func main() {
+	// "pacgo config info" is a subcommand, not a flag, so it has to be
+	// dispatched before flag.Parse() gets anywhere near the game's own flags.
+	if len(os.Args) > 1 && os.Args[1] == "config" {
+		runConfigCommand(os.Args[2:])
+		return
+	}
+
 	// Initialise command line flags
 	flag.Parse() // Need to call this ** before ** changing the console to cbreak mode as it calls os.Exit() on error
 
@@ -486,87 +612,72 @@ func main() {
 	initialise()
 	defer cleanup()
 
-	// Load maze with error checking
-	err := loadMaze(*mazeFile)
-	if err != nil {
-		log.Println("failed to load maze:", err)
-		return
-	}
+	initScoreStore()
 
 	// load resources
-	err = loadConfig(*configFile) // Load json config
+	err := loadConfig(*configFile) // Load json config
 	if err != nil {
 		log.Println("failed to load configuration:", err)
 		return
 	}
 
-	// process input (async)
-	input := make(chan string)
-	go func(ch chan<- string) {
-		for {
-			input, err := readInput()
-			if err != nil {
-				log.Println("error reading input:", err)
-				ch <- "ESC"
-			}
-			ch <- input
+	startGhostModeCycle()
+
+	// Resolve the level pack: a real pack directory if -pack was given,
+	// otherwise a synthetic single-level pack around -maze-file.
+	var pack *LevelPack
+	var dir string
+	if *packDir != "" {
+		pack, err = loadLevelPack(*packDir)
+		if err != nil {
+			log.Println("failed to load level pack:", err)
+			return
 		}
-	}(input)
+		dir = *packDir
+	} else {
+		pack, dir = singleMazePack(*mazeFile)
+	}
 
-	// game loop
-	for {
-		// process movement
-		select { // Select is a switch statement for channels
-		case inp := <-input:
-			if inp == "ESC" {
-				lives = 0
-			}
-			movePlayer(inp)
-		default:
-		}
+	level := *startLevel - 1
+	if level < 0 || level >= len(pack.Levels) {
+		log.Printf("level %d out of range for pack %q (has %d levels)\n", *startLevel, pack.Name, len(pack.Levels))
+		return
+	}
 
-		moveGhosts()
-
-		// process collisions
-
-		// Interesting that you can compare objects as player == *g (not reference based comparison like Java)
-
-		for _, g := range ghosts {
-			if player.row == g.position.row && player.col == g.position.col {
-				ghostStatusMx.RLock() // Lock Read mutex
-				if g.status == GhostStatusNormal {
-					lives--
-					if lives != 0 {
-						moveCursor(player.row, player.col)
-						fmt.Print(cfg.Death)
-						moveCursor(len(maze)+2, 0)
-						ghostStatusMx.RUnlock()
-						time.Sleep(1000 * time.Millisecond) // Long respawn timer
-						player.row, player.col = player.startRow, player.startRow
-					}
-				} else if g.status == GhostStatusBlue {
-					ghostStatusMx.RUnlock()
-					updateGhosts([]*ghost{g}, GhostStatusNormal)
-					g.position.row, g.position.col = g.position.startRow, g.position.startCol
-				}
-			}
-		}
+	game, err := NewGame(pack, dir, level)
+	if err != nil {
+		log.Println("failed to start game:", err)
+		return
+	}
+	currentGame = game
 
-		// update screen
-		printScreen()
+	if *httpAddr != "" {
+		startHTTPServer(*httpAddr)
+	}
 
-		// check game over
-		if numDots == 0 || lives <= 0 {
-			// If dead, print the death emoji
-			if lives == 0 {
-				moveCursor(player.row, player.col)
-				fmt.Print(cfg.Death)
-				moveCursor(len(maze)+2, 0)
-			}
-			break
-		}
+	// The single stdin reader runs for the rest of the process; everything
+	// that wants a keystroke consumes from rawInput (or from the input
+	// channel fed by translateInput below) instead of reading Stdin itself.
+	rawInput := make(chan rawRead)
+	go runStdinReader(rawInput)
 
-		// repeat
-		time.Sleep(150 * time.Millisecond)
-	}
+	printTitleScreen(rawInput)
+
+	// process input (async), forwarding parsed directions to the game loop
+	// until done is closed
+	input := make(chan string)
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+	go translateInput(rawInput, input, done, stopped)
+
+	game.Run(input)
+
+	// Stop translateInput and wait for it to actually exit before reading
+	// rawInput directly for the initials prompt, so the two never compete
+	// for the same keystrokes.
+	close(done)
+	<-stopped
+
+	recordScore(game, rawInput)
+	printTopScores()
 }
@@ -0,0 +1,142 @@
+package main
+
+/*
+	Scoreboard
+
+	Wires the scores package into the game: a high-score table is shown on
+	the title screen and again after death, and the player's initials are
+	captured and persisted once a run ends.
+*/
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"pacgo/source/scores"
+
+	"github.com/danicat/simpleansi"
+)
+
+// scoreStore is the active high-score backend: a JSON file by default, or
+// an in-memory store if the default path can't be resolved.
+var scoreStore scores.Store
+
+/*
+	initScoreStore picks the score storage backend. It favours the JSON-file
+	backend at ~/.pacgo/scores.json, falling back to an in-memory store (so
+	the game is still playable, just without persistence) if the home
+	directory can't be resolved.
+*/
+func initScoreStore() {
+	path, err := scores.DefaultPath()
+	if err != nil {
+		log.Println("falling back to in-memory high scores:", err)
+		scoreStore = scores.NewMemoryStore()
+		return
+	}
+	scoreStore = scores.NewFileStore(path)
+}
+
+/*
+	printTopScores renders the top 10 entries from scoreStore.
+*/
+func printTopScores() {
+	top, err := scoreStore.Top(10)
+	if err != nil {
+		log.Println("failed to load high scores:", err)
+		return
+	}
+
+	fmt.Println("Top scores:")
+	if len(top) == 0 {
+		fmt.Println("  (no scores yet)")
+	}
+	for i, entry := range top {
+		fmt.Printf("  %2d. %-3s %6d  level %d  %s\n", i+1, entry.Initials, entry.Score, entry.LevelReached, entry.PackName)
+	}
+}
+
+/*
+	printTitleScreen clears the screen, shows the high-score table, and
+	waits for a keypress before the game loop begins. raw is the shared
+	rawRead channel fed by the program's single stdin reader goroutine.
+*/
+func printTitleScreen(raw <-chan rawRead) {
+	simpleansi.ClearScreen()
+	fmt.Println("pacgo")
+	fmt.Println()
+	printTopScores()
+	fmt.Println()
+	fmt.Println("Press any key to start...")
+	waitForKeypress(raw)
+}
+
+/*
+	waitForKeypress blocks until a rawRead arrives on raw, i.e. until any
+	key is pressed.
+*/
+func waitForKeypress(raw <-chan rawRead) {
+	<-raw
+}
+
+/*
+	promptInitials reads exactly 3 characters from raw, echoing each
+	keystroke back since cbreak mode disables the terminal's own echo. ESC
+	ends input early and pads the remainder with underscores. raw must have
+	no other concurrent consumer (see the close(done)/<-stopped handshake
+	in main, which hands raw over from translateInput before this is
+	called), since two goroutines racing to receive from the same channel
+	would each only get some of the keystrokes.
+*/
+func promptInitials(raw <-chan rawRead) string {
+	fmt.Print("Enter your initials: ")
+
+	initials := make([]byte, 0, 3)
+
+readLoop:
+	for len(initials) < 3 {
+		r := <-raw
+		if r.err != nil {
+			break
+		}
+		for _, ch := range r.buf {
+			if ch == 0x1b { // ESC
+				break readLoop
+			}
+			initials = append(initials, ch)
+			fmt.Printf("%c", ch)
+			if len(initials) == 3 {
+				break
+			}
+		}
+	}
+	fmt.Println()
+
+	for len(initials) < 3 {
+		initials = append(initials, '_')
+	}
+
+	return strings.ToUpper(string(initials))
+}
+
+/*
+	recordScore prompts for the player's initials and saves a score entry
+	for the run that just ended.
+*/
+func recordScore(g *Game, raw <-chan rawRead) {
+	initials := promptInitials(raw)
+
+	entry := scores.Entry{
+		Initials:     initials,
+		Score:        score,
+		LevelReached: g.LevelIndex + 1,
+		PackName:     g.pack.Name,
+		Timestamp:    time.Now(),
+	}
+
+	if err := scoreStore.Save(entry); err != nil {
+		log.Println("failed to save score:", err)
+	}
+}
@@ -0,0 +1,169 @@
+// Package scores persists pacgo high scores behind a small Store interface,
+// so the game loop doesn't need to care whether entries end up on disk or
+// just in memory (as used by tests, and by the game when the default score
+// file path can't be resolved).
+package scores
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Entry is a single high-score record.
+type Entry struct {
+	Initials     string    `json:"initials"`
+	Score        int       `json:"score"`
+	LevelReached int       `json:"level_reached"`
+	PackName     string    `json:"pack_name"`
+	Timestamp    time.Time `json:"timestamp"`
+}
+
+// Store persists and retrieves high-score entries.
+type Store interface {
+	Save(entry Entry) error
+	Top(n int) ([]Entry, error)
+}
+
+// DefaultPath returns the default location for the JSON score file,
+// ~/.pacgo/scores.json.
+func DefaultPath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".pacgo", "scores.json"), nil
+}
+
+// sortByScoreDesc sorts entries from highest to lowest score.
+func sortByScoreDesc(entries []Entry) {
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].Score > entries[j].Score
+	})
+}
+
+func top(entries []Entry, n int) []Entry {
+	sortByScoreDesc(entries)
+	if n > len(entries) {
+		n = len(entries)
+	}
+	return append([]Entry{}, entries[:n]...)
+}
+
+// MemoryStore is an in-memory Store, used in tests and anywhere persistence
+// to disk isn't wanted.
+type MemoryStore struct {
+	mu      sync.Mutex
+	entries []Entry
+}
+
+// NewMemoryStore returns an empty in-memory Store.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{}
+}
+
+func (s *MemoryStore) Save(entry Entry) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.entries = append(s.entries, entry)
+	return nil
+}
+
+func (s *MemoryStore) Top(n int) ([]Entry, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return top(s.entries, n), nil
+}
+
+// FileStore is a JSON-file backed Store. Writes are atomic (temp file +
+// rename) so a crash mid-write can't corrupt the score file, and a mutex
+// serialises Save and Top calls made on the same FileStore from different
+// goroutines.
+type FileStore struct {
+	path string
+	mu   sync.Mutex
+}
+
+// NewFileStore returns a Store backed by the JSON file at path. The file and
+// its parent directory are created on first Save if they don't exist.
+func NewFileStore(path string) *FileStore {
+	return &FileStore{path: path}
+}
+
+func (s *FileStore) load() ([]Entry, error) {
+	data, err := os.ReadFile(s.path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(data) == 0 {
+		return nil, nil
+	}
+
+	var entries []Entry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+func (s *FileStore) Save(entry Entry) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entries, err := s.load()
+	if err != nil {
+		return err
+	}
+	entries = append(entries, entry)
+
+	return s.writeAtomic(entries)
+}
+
+func (s *FileStore) Top(n int) ([]Entry, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entries, err := s.load()
+	if err != nil {
+		return nil, err
+	}
+	return top(entries, n), nil
+}
+
+// writeAtomic writes entries to a temp file in the same directory, then
+// renames it over the target path, so a crash mid-write leaves the previous
+// file intact rather than a truncated one.
+func (s *FileStore) writeAtomic(entries []Entry) error {
+	dir := filepath.Dir(s.path)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(dir, "scores-*.json.tmp")
+	if err != nil {
+		return err
+	}
+	tmpPath := tmp.Name()
+	defer os.Remove(tmpPath) // no-op once the rename below succeeds
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmpPath, s.path)
+}
@@ -0,0 +1,102 @@
+package scores
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func entry(initials string, score int) Entry {
+	return Entry{Initials: initials, Score: score, LevelReached: 1, PackName: "classic", Timestamp: time.Now()}
+}
+
+// assertTop checks that Top(n) is sorted highest-score-first and contains
+// exactly the expected initials.
+func assertTop(t *testing.T, got []Entry, wantInitials ...string) {
+	t.Helper()
+	if len(got) != len(wantInitials) {
+		t.Fatalf("Top returned %d entries, want %d: %+v", len(got), len(wantInitials), got)
+	}
+	for i, want := range wantInitials {
+		if got[i].Initials != want {
+			t.Errorf("entry %d: got initials %q, want %q", i, got[i].Initials, want)
+		}
+	}
+}
+
+func TestMemoryStoreRoundTrip(t *testing.T) {
+	s := NewMemoryStore()
+
+	for _, e := range []Entry{entry("AAA", 100), entry("BBB", 300), entry("CCC", 200)} {
+		if err := s.Save(e); err != nil {
+			t.Fatalf("Save: %v", err)
+		}
+	}
+
+	top, err := s.Top(2)
+	if err != nil {
+		t.Fatalf("Top: %v", err)
+	}
+	assertTop(t, top, "BBB", "CCC")
+}
+
+func TestFileStoreRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nested", "scores.json")
+	s := NewFileStore(path)
+
+	for _, e := range []Entry{entry("AAA", 100), entry("BBB", 300), entry("CCC", 200)} {
+		if err := s.Save(e); err != nil {
+			t.Fatalf("Save: %v", err)
+		}
+	}
+
+	if _, err := os.Stat(path); err != nil {
+		t.Fatalf("expected Save to create %s: %v", path, err)
+	}
+
+	// A fresh FileStore pointed at the same path should see everything the
+	// first one saved, proving the round trip goes through disk and not
+	// just an in-memory cache.
+	reopened := NewFileStore(path)
+	top, err := reopened.Top(10)
+	if err != nil {
+		t.Fatalf("Top: %v", err)
+	}
+	assertTop(t, top, "BBB", "CCC", "AAA")
+}
+
+// TestFileStoreSurvivesCrashMidWrite simulates writeAtomic's temp file being
+// left behind by a process that crashed before the rename - the classic
+// failure mode atomic-write-then-rename is meant to guard against - and
+// checks that Top still returns the last successfully written data rather
+// than tripping over the stray temp file.
+func TestFileStoreSurvivesCrashMidWrite(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "scores.json")
+	s := NewFileStore(path)
+
+	if err := s.Save(entry("AAA", 100)); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	stray := filepath.Join(filepath.Dir(path), "scores-crash.json.tmp")
+	if err := os.WriteFile(stray, []byte("not valid json, a half-written crash artefact"), 0o644); err != nil {
+		t.Fatalf("writing stray temp file: %v", err)
+	}
+	defer os.Remove(stray)
+
+	top, err := s.Top(10)
+	if err != nil {
+		t.Fatalf("Top: %v", err)
+	}
+	assertTop(t, top, "AAA")
+
+	if err := s.Save(entry("BBB", 300)); err != nil {
+		t.Fatalf("Save after stray temp file present: %v", err)
+	}
+	top, err = s.Top(10)
+	if err != nil {
+		t.Fatalf("Top: %v", err)
+	}
+	assertTop(t, top, "BBB", "AAA")
+}